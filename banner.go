@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+)
+
+// maxBannerSize limits how much of the response is read while looking for the expected string
+const maxBannerSize = 4096
+
+// unescapeSend replaces \r, \n and \t escapes, so protocol commands can be passed as flags, ex: PING\r\n
+var unescapeSend = strings.NewReplacer(`\r`, "\r", `\n`, "\n", `\t`, "\t")
+
+// checkBanner - writes Send to an open connection and reads the response until it contains Expect
+func (search *Search) checkBanner(ctx context.Context, conn net.Conn, result SearchResult) SearchResult {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if search.Send != "" {
+		if _, err := conn.Write([]byte(unescapeSend.Replace(search.Send))); err != nil {
+			result.State = "Failed"
+			result.Reason = failureReason(err)
+			return result
+		}
+	}
+
+	if search.Expect == "" {
+		result.State = "Success"
+		return result
+	}
+
+	response := make([]byte, 0, maxBannerSize)
+	buf := make([]byte, 512)
+	for len(response) < maxBannerSize && !bytes.Contains(response, []byte(search.Expect)) {
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if err != nil {
+			break
+		}
+	}
+
+	result.Banner = firstLine(response)
+	if !bytes.Contains(response, []byte(search.Expect)) {
+		result.State = "Failed"
+		result.Reason = "unexpected_response"
+		return result
+	}
+
+	result.State = "Success"
+	return result
+}
+
+// firstLine returns the first line of the response without line endings
+func firstLine(response []byte) string {
+	line, _, _ := bytes.Cut(response, []byte("\n"))
+	return strings.TrimRight(string(line), "\r")
+}
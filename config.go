@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secrets in the printed config
+const redacted = "REDACTED"
+
+// secretHeaders are the request headers whose values are redacted in the printed config
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// printConfig - writes the effective value of every flag, after the defaults, the URLCHECKER_
+// environment variables and the command line are merged, as a JSON object. Secrets are redacted.
+func printConfig(w io.Writer, flags *flag.FlagSet) error {
+	config := make(map[string]any)
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Name == "print-config" {
+			return
+		}
+		if list, ok := f.Value.(*listFlag); ok {
+			values := make([]string, len(list.values))
+			for i, value := range list.values {
+				values[i] = redactFlag(f.Name, value)
+			}
+			config[f.Name] = values
+			return
+		}
+		config[f.Name] = redactFlag(f.Name, f.Value.String())
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
+
+// redactFlag hides the token, the passwords of the proxy and the pushgateway and the value of secret headers
+func redactFlag(name, value string) string {
+	switch name {
+	case "influx-token":
+		if value != "" {
+			return redacted
+		}
+	case "proxy", "pushgateway":
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+	case "header":
+		header, _, _ := strings.Cut(value, ":")
+		for _, secret := range secretHeaders {
+			if http.CanonicalHeaderKey(strings.TrimSpace(header)) == secret {
+				return header + ": " + redacted
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"time"
+)
+
+// Statistics aggregates the repeated checks of an address, port and path with --count, like ping does.
+// With --all-ips every address of the host is aggregated on its own.
+type Statistics struct {
+	Address     string        `json:"address"`
+	Port        string        `json:"port"`
+	Path        string        `json:"path,omitempty"`
+	IP          string        `json:"ip,omitempty"`
+	Transmitted int           `json:"transmitted"`
+	Received    int           `json:"received"`
+	LossPercent float64       `json:"loss_percent"`
+	Min         time.Duration `json:"min"`
+	Avg         time.Duration `json:"avg"`
+	Max         time.Duration `json:"max"`
+	StdDev      time.Duration `json:"stddev"`
+	// Apdex is the share of satisfied checks, with tolerating ones counted half, ex: 0.85 (with an Apdex target only)
+	Apdex *float64 `json:"apdex,omitempty"`
+}
+
+// statisticsKey identifies the checks aggregated together
+type statisticsKey struct {
+	address, port, path, ip string
+}
+
+// newStatistics - aggregates results per address, port, path and IP in the order they were first checked,
+// the response times are taken only from successful checks. Checks canceled by Ctrl+C are not counted.
+// With an apdexTarget T a check is satisfied within T, tolerating within 4T, and frustrated above 4T or when it failed.
+func newStatistics(results []SearchResult, apdexTarget time.Duration) []Statistics {
+	var (
+		stats []Statistics
+		times [][]time.Duration
+		index = map[statisticsKey]int{}
+	)
+	for _, result := range results {
+		if result.Reason == "canceled" {
+			continue
+		}
+		key := statisticsKey{result.Address, result.Port, result.Path, result.IP}
+		i, ok := index[key]
+		if !ok {
+			i = len(stats)
+			index[key] = i
+			stats = append(stats, Statistics{Address: result.Address, Port: result.Port, Path: result.Path, IP: result.IP})
+			times = append(times, nil)
+		}
+		stats[i].Transmitted++
+		if result.State == "Success" {
+			stats[i].Received++
+			times[i] = append(times[i], result.ResponseTime)
+		}
+	}
+
+	for i := range stats {
+		s := &stats[i]
+		s.LossPercent = float64(s.Transmitted-s.Received) * 100 / float64(s.Transmitted)
+
+		durations := times[i]
+		if len(durations) == 0 {
+			continue
+		}
+		var sum time.Duration
+		s.Min, s.Max = durations[0], durations[0]
+		for _, d := range durations {
+			sum += d
+			s.Min, s.Max = min(s.Min, d), max(s.Max, d)
+		}
+		s.Avg = sum / time.Duration(len(durations))
+
+		var variance float64
+		for _, d := range durations {
+			variance += math.Pow(float64(d-s.Avg), 2)
+		}
+		s.StdDev = time.Duration(math.Sqrt(variance / float64(len(durations))))
+	}
+
+	if apdexTarget > 0 {
+		for i := range stats {
+			s := &stats[i]
+			var score float64
+			for _, d := range times[i] {
+				switch {
+				case d <= apdexTarget:
+					score++
+				case d <= 4*apdexTarget:
+					score += 0.5
+				}
+			}
+			apdex := score / float64(s.Transmitted)
+			s.Apdex = &apdex
+		}
+	}
+	return stats
+}
+
+// String - formats the statistics as a summary line for the console output
+func (s Statistics) String() string {
+	addr := net.JoinHostPort(s.Address, s.Port) + s.Path
+	if s.IP != "" {
+		addr += " via " + s.IP
+	}
+	line := fmt.Sprintf("%v: %v checks, %v successful, %.1f%% loss, min/avg/max/stddev = %v/%v/%v/%v",
+		addr, s.Transmitted, s.Received, s.LossPercent,
+		s.Min.Round(time.Microsecond), s.Avg.Round(time.Microsecond), s.Max.Round(time.Microsecond), s.StdDev.Round(time.Microsecond))
+	if s.Apdex != nil {
+		line += fmt.Sprintf(", apdex %.2f", *s.Apdex)
+	}
+	return line
+}
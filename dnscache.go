@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dnsCache keeps the addresses of hosts for a TTL, so repeated checks, ex: with --count,
+// don't resolve the same host again. Failed resolutions are not cached.
+type dnsCache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+
+	hits, misses atomic.Int64
+}
+
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+// newDNSCache - creates a cache in front of lookup
+func newDNSCache(ttl time.Duration, lookup func(ctx context.Context, host string) ([]net.IPAddr, error)) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		lookup:  lookup,
+		entries: map[string]dnsEntry{},
+	}
+}
+
+// Lookup - returns the cached addresses of host, or resolves them when they are missing or expired
+func (c *dnsCache) Lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		c.hits.Add(1)
+		return entry.addrs, nil
+	}
+	c.misses.Add(1)
+
+	addrs, err := c.lookup(ctx, host)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		delete(c.entries, host)
+		return nil, err
+	}
+	c.entries[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	return addrs, nil
+}
+
+// Stats - returns how many lookups were answered from the cache and how many were resolved
+func (c *dnsCache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+// parsePrefer parses the preferred address family of checks, ex: ipv4, ipv6 or auto
+func parsePrefer(s string) (string, error) {
+	switch s {
+	case "ipv4", "ipv6", "auto":
+		return s, nil
+	}
+	return "", errors.New("invalid address family: " + s + ", use ipv4, ipv6 or auto")
+}
+
+// network - restricts network to the preferred address family, with auto the dialer
+// races IPv4 and IPv6 and keeps whichever connects first
+func (search *Search) network(network string) string {
+	if network != "tcp" && network != "udp" {
+		return network
+	}
+	switch search.Prefer {
+	case "ipv4":
+		return network + "4"
+	case "ipv6":
+		return network + "6"
+	}
+	return network
+}
+
+// prefers reports whether ip is in the preferred address family
+func (search *Search) prefers(ip net.IP) bool {
+	switch search.Prefer {
+	case "ipv4":
+		return ip.To4() != nil
+	case "ipv6":
+		return ip.To4() == nil
+	}
+	return true
+}
+
+// addrFamily returns the address family of a connected address, ex: ipv4
+func addrFamily(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return ""
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return "ipv4"
+	}
+	return "ipv6"
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// fileSDGroup is a target group of the Prometheus file_sd format
+type fileSDGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// fileSDTargets - returns the checked endpoints as targets, host:port or the url of http checks
+func (search *Search) fileSDTargets(urls []string) []string {
+	targets := make([]string, 0, len(urls))
+	for _, url := range urls {
+		host, port, path := parseTarget(url, search.Port)
+		target := net.JoinHostPort(host, port)
+		if search.Protocol == "http" || search.Protocol == "https" {
+			target = search.Protocol + "://" + target + path
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// writeFileSD - writes the targets for Prometheus file based service discovery. The file is written
+// next to the destination and renamed, so Prometheus never reads a partial file.
+func writeFileSD(filename, protocol string, targets []string) error {
+	data, err := json.MarshalIndent([]fileSDGroup{{
+		Targets: targets,
+		Labels:  map[string]string{"protocol": protocol},
+	}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
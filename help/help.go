@@ -9,10 +9,11 @@ func Show() {
 	< URL-checker >
 	 -------------
 	`)
-	fmt.Println("Usage: urlchecker --url <url>")
+	fmt.Println("Usage: urlchecker check --url <url>")
 	fmt.Println("")
-	fmt.Println("urlchecker --url <url> --port <port>")
-	fmt.Println("urlchecker --file <filename>")
+	fmt.Println("urlchecker check --url <url> --port <port>")
+	fmt.Println("urlchecker check --file <filename>")
+	fmt.Println("urlchecker config print [flags]")
 	fmt.Println("")
-	fmt.Println("For more information try --help")
+	fmt.Println("For more information try urlchecker check --help")
 }
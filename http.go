@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// StatusRange is an inclusive range of HTTP status codes
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// parseStatusRanges parses a list of expected status codes, ex: 200,301-302,400-499
+func parseStatusRanges(s string) ([]StatusRange, error) {
+	ranges := make([]StatusRange, 0)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+
+		fromCode, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, errors.New("invalid status code: " + part)
+		}
+		toCode, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, errors.New("invalid status code: " + part)
+		}
+		if fromCode < 100 || toCode > 599 || fromCode > toCode {
+			return nil, errors.New("invalid status range: " + part)
+		}
+
+		ranges = append(ranges, StatusRange{From: fromCode, To: toCode})
+	}
+
+	if len(ranges) == 0 {
+		return nil, errors.New("expected status can't be empty")
+	}
+
+	return ranges, nil
+}
+
+// statusExpected reports whether the status code falls in one of the ranges
+func statusExpected(code int, ranges []StatusRange) bool {
+	for _, r := range ranges {
+		if code >= r.From && code <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultMaxBodySize is how much of the body is matched by ExpectBody and RejectBody
+const defaultMaxBodySize = 64 * 1024
+
+// errTooManyRedirects stops following redirects over MaxRedirects
+var errTooManyRedirects = errors.New("too many redirects")
+
+// maxBodySnippet limits the part of the body reported with unexpected_body
+const maxBodySnippet = 100
+
+// parseBodyRegexp compiles the regexp of a body flag, an empty one disables the assertion
+func parseBodyRegexp(name, expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v regexp: %v", name, err)
+	}
+	return re, nil
+}
+
+// bodySnippet returns the start of the body on a single line
+func bodySnippet(body []byte) string {
+	if len(body) > maxBodySnippet {
+		body = body[:maxBodySnippet]
+	}
+	return strings.Join(strings.Fields(strings.ToValidUTF8(string(body), "")), " ")
+}
+
+// parseHeaders parses request headers in the "Name: value" form, a header can be given several times
+func parseHeaders(values []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, errors.New("invalid header: " + value + ", use Name: value")
+		}
+		headers.Add(name, strings.TrimSpace(v))
+	}
+	return headers, nil
+}
+
+// checkHTTP - checks url address by issuing a request and matching the status code and the body
+func (search *Search) checkHTTP(ctx context.Context, result SearchResult) SearchResult {
+	ctx, span := tracer.Start(ctx, "http request")
+	defer span.End()
+
+	// the phases are timed from the hooks, which may run on the goroutine of the dial
+	var (
+		mu                                         sync.Mutex
+		tlsFailed                                  bool
+		dnsStart, connectStart, tlsStart, reqStart time.Time
+	)
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			result.DNSTime = time.Since(dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			mu.Lock()
+			defer mu.Unlock()
+			connectStart = time.Now()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				result.ConnectTime = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				tlsFailed = true
+				return
+			}
+			result.TLSTime = time.Since(tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			result.Family = addrFamily(info.Conn.RemoteAddr())
+			result.answered = true
+		},
+		GotFirstResponseByte: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			result.TTFB = time.Since(reqStart)
+		},
+	}
+
+	path := result.Path
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+	addr := net.JoinHostPort(result.Address, result.Port)
+	var body io.Reader
+	if search.Body != "" {
+		body = strings.NewReader(search.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, search.Method, search.Protocol+"://"+addr+path, body)
+	if err != nil {
+		result.State = "Failed"
+		result.Reason = "invalid_request"
+		return result
+	}
+	for name, values := range search.Headers {
+		req.Header[name] = values
+	}
+	// net/http takes the virtual host from req.Host, not from the headers
+	serverName := ""
+	if host := search.Headers.Get("Host"); host != "" {
+		req.Host = host
+		serverName, _ = splitHostPort(host, "")
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	client := &http.Client{
+		Timeout: search.Timeout,
+		// every check measures a fresh connection, which is closed with the response instead of idling
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext:       search.dialTraced,
+			TLSClientConfig:   &tls.Config{ServerName: serverName, InsecureSkipVerify: search.InsecureSkipVerify},
+		},
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if !search.FollowRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > search.MaxRedirects {
+				return errTooManyRedirects
+			}
+			return nil
+		},
+	}
+
+	mu.Lock()
+	reqStart = time.Now()
+	mu.Unlock()
+	resp, err := client.Do(req)
+	mu.Lock()
+	defer mu.Unlock()
+	result.ResponseTime = time.Since(reqStart)
+	if err != nil {
+		if tlsFailed {
+			result.State = "TLSFailed"
+			result.Reason = "tls_error"
+			return result
+		}
+		result.State = "Failed"
+		result.Reason = failureReason(err)
+		if errors.Is(err, errTooManyRedirects) {
+			result.Reason = "too_many_redirects"
+		}
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.Request.Response != nil {
+		result.FinalURL = resp.Request.URL.String()
+		for r := resp.Request.Response; r != nil; r = r.Request.Response {
+			result.Redirects++
+		}
+	}
+
+	result.StatusCode = resp.StatusCode
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if !statusExpected(resp.StatusCode, search.ExpectedStatus) {
+		result.State = "Failed"
+		result.Reason = "unexpected_status"
+		return result
+	}
+
+	if search.ExpectBody != nil || search.RejectBody != nil {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, search.MaxBodySize))
+		if err != nil {
+			result.State = "Failed"
+			result.Reason = failureReason(err)
+			return result
+		}
+		if (search.ExpectBody != nil && !search.ExpectBody.Match(body)) || (search.RejectBody != nil && search.RejectBody.Match(body)) {
+			result.State = "Failed"
+			result.Reason = fmt.Sprintf("unexpected_body: %q", bodySnippet(body))
+			return result
+		}
+	}
+
+	result.State = "Success"
+	return result
+}
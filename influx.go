@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// influxRecorder batches check results as InfluxDB line protocol points and writes them on Close
+type influxRecorder struct {
+	writeURL string
+	token    string
+	client   *http.Client
+	points   []string
+}
+
+// newInfluxRecorder - creates a recorder writing to an InfluxDB v2 bucket, ex: http://localhost:8086
+func newInfluxRecorder(serverURL, org, bucket, token string) (*influxRecorder, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid influx url: %v", serverURL)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("influx bucket is required")
+	}
+
+	u = u.JoinPath("/api/v2/write")
+	u.RawQuery = url.Values{"org": {org}, "bucket": {bucket}, "precision": {"ns"}}.Encode()
+
+	return &influxRecorder{
+		writeURL: u.String(),
+		token:    token,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// RecordCheck - adds a point with the state, the up field and the response time in seconds of a result
+func (r *influxRecorder) RecordCheck(protocol string, result SearchResult) error {
+	tags := fmt.Sprintf("url=%v,port=%v,protocol=%v", influxTag(result.Address), influxTag(result.Port), influxTag(protocol))
+	// the path and the address tell apart the points of one host and port, an empty tag is invalid
+	if result.Path != "" {
+		tags += ",path=" + influxTag(result.Path)
+	}
+	if result.IP != "" {
+		tags += ",ip=" + influxTag(result.IP)
+	}
+	r.points = append(r.points, fmt.Sprintf("urlcheck,%v state=\"%v\",up=%v,response_time=%v %v", tags,
+		strings.NewReplacer(`"`, `\"`, `\`, `\\`).Replace(result.State),
+		result.State == "Success", result.ResponseTime.Seconds(), result.Timestamp.UnixNano()))
+	return nil
+}
+
+// Close - writes all points of the run in one request
+func (r *influxRecorder) Close() error {
+	if len(r.points) == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.writeURL, bytes.NewBufferString(strings.Join(r.points, "\n")))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Token "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot write to influx: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("cannot write to influx: %v %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// influxTag escapes commas, equal signs and spaces in a tag value
+func influxTag(value string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(value)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// CheckIPs - resolves the host of url and checks every address behind it, the results
+// keep the host as Address and store the checked address in IP
+func (search *Search) CheckIPs(ctx context.Context, url string) []SearchResult {
+	host, port, _ := parseTarget(url, search.Port)
+
+	addrs, err := search.lookup(ctx, host)
+	if err != nil {
+		return []SearchResult{{
+			Address:   host,
+			Port:      port,
+			State:     "Failed",
+			Reason:    failureReason(err),
+			Timestamp: time.Now(),
+		}}
+	}
+
+	results := make([]SearchResult, 0, len(addrs))
+	healthy := 0
+	for _, addr := range addrs {
+		if !search.prefers(addr.IP) {
+			continue
+		}
+		ip := addr.String()
+
+		// the copy dials the resolved address, while tls and http still use the host name
+		ipSearch := *search
+		ipSearch.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			_, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			return search.dial(ctx, network, net.JoinHostPort(ip, port))
+		}
+
+		result := ipSearch.Check(ctx, url)
+		result.IP = ip
+		if result.State == "Success" {
+			healthy++
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return []SearchResult{{
+			Address:   host,
+			Port:      port,
+			State:     "Failed",
+			Reason:    "dns_error",
+			Timestamp: time.Now(),
+		}}
+	}
+
+	for i := range results {
+		results[i].HealthyIPs = healthy
+		results[i].TotalIPs = len(results)
+	}
+	return results
+}
+
+// quorum returns how many of total addresses must pass for a host to be healthy
+func (search *Search) quorum(total int) int {
+	if search.Quorum <= 0 || search.Quorum > total {
+		return total
+	}
+	return search.Quorum
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// newLogger - creates the logger for operational messages, the check results are printed separately
+func newLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, errors.New("invalid log level: " + level + ", use debug, info, warn or error")
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel}
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	}
+
+	return nil, errors.New("invalid log format: " + format + ", use text or json")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Nagios plugin exit codes
+const (
+	nagiosOK = iota
+	nagiosWarning
+	nagiosCritical
+	nagiosUnknown
+)
+
+var nagiosLabels = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// Nagios - formats the result of a check as a Nagios plugin output and returns the plugin exit code
+func (search *Search) Nagios(result SearchResult) (string, int) {
+	addr := fmt.Sprintf("%v %v", search.Protocol, net.JoinHostPort(result.Address, result.Port))
+	responseTime := result.ResponseTime.Round(time.Microsecond)
+
+	var (
+		code    int
+		message string
+	)
+	switch {
+	case result.State != "Success":
+		code = nagiosCritical
+		message = fmt.Sprintf("%v failed (%v)", addr, result.Reason)
+	case result.ExpectDown:
+		code = nagiosOK
+		message = fmt.Sprintf("%v is down as expected (%v)", addr, result.Reason)
+	case search.CritThreshold > 0 && result.ResponseTime > search.CritThreshold:
+		code = nagiosCritical
+		message = fmt.Sprintf("%v responded in %v, over critical threshold %v", addr, responseTime, search.CritThreshold)
+	case search.WarnThreshold > 0 && result.ResponseTime > search.WarnThreshold:
+		code = nagiosWarning
+		message = fmt.Sprintf("%v responded in %v, over warning threshold %v", addr, responseTime, search.WarnThreshold)
+	default:
+		code = nagiosOK
+		message = fmt.Sprintf("%v responded in %v", addr, responseTime)
+	}
+
+	perfdata := fmt.Sprintf("response_time=%.6fs", result.ResponseTime.Seconds())
+	if search.WarnThreshold > 0 || search.CritThreshold > 0 {
+		perfdata += fmt.Sprintf(";%v;%v", perfThreshold(search.WarnThreshold), perfThreshold(search.CritThreshold))
+	}
+
+	return fmt.Sprintf("%v - %v | %v", nagiosLabels[code], message, perfdata), code
+}
+
+// NagiosUnknown - formats a config or argument error as a Nagios plugin output
+func NagiosUnknown(err error) (string, int) {
+	return fmt.Sprintf("%v - %v", nagiosLabels[nagiosUnknown], err), nagiosUnknown
+}
+
+// perfThreshold formats a threshold in seconds for perfdata, an unset threshold stays empty
+func perfThreshold(threshold time.Duration) string {
+	if threshold <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.6f", threshold.Seconds())
+}
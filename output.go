@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"text/template"
+)
+
+// HealthCheckResult is the single JSON document printed with --json, its schema is the same
+// for any number of results
+type HealthCheckResult struct {
+	Results        []SearchResult `json:"results"`
+	Summary        Summary        `json:"summary"`
+	OverallHealthy bool           `json:"overall_healthy"`
+	// Statistics aggregate the checks of every address and port with --count
+	Statistics []Statistics `json:"statistics,omitempty"`
+
+	// urls keeps whether every url counted in the summary is still healthy
+	urls map[string]bool
+}
+
+// Summary counts the checked urls, a url checked several times with --count or over several
+// addresses with --all-ips is counted once and is unhealthy if any of its checks is
+type Summary struct {
+	TotalURLs     int `json:"total_urls"`
+	HealthyURLs   int `json:"healthy_urls"`
+	UnhealthyURLs int `json:"unhealthy_urls"`
+}
+
+// NewHealthCheckResult initializes an empty HealthCheckResult, which is healthy until a failure is added
+func NewHealthCheckResult() *HealthCheckResult {
+	return &HealthCheckResult{
+		Results:        make([]SearchResult, 0),
+		OverallHealthy: true,
+	}
+}
+
+// Add - adds the result of a check and updates the summary of its url
+func (report *HealthCheckResult) Add(result SearchResult, healthy bool) {
+	report.Results = append(report.Results, result)
+	if !healthy {
+		report.OverallHealthy = false
+	}
+
+	if report.urls == nil {
+		report.urls = make(map[string]bool)
+	}
+	url := net.JoinHostPort(result.Address, result.Port) + result.Path
+	wasHealthy, seen := report.urls[url]
+	switch {
+	case !seen:
+		report.urls[url] = healthy
+		report.Summary.TotalURLs++
+		if healthy {
+			report.Summary.HealthyURLs++
+		} else {
+			report.Summary.UnhealthyURLs++
+		}
+	case wasHealthy && !healthy:
+		report.urls[url] = false
+		report.Summary.HealthyURLs--
+		report.Summary.UnhealthyURLs++
+	}
+}
+
+// Sort - orders the results by address and then by port, so the ports of a host are grouped together
+func (report *HealthCheckResult) Sort() {
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		a, b := report.Results[i], report.Results[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		portA, errA := strconv.Atoi(a.Port)
+		portB, errB := strconv.Atoi(b.Port)
+		if errA != nil || errB != nil {
+			return a.Port < b.Port
+		}
+		return portA < portB
+	})
+}
+
+// writeJSON - writes all results as one indented JSON document
+func writeJSON(w io.Writer, report *HealthCheckResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// writeNDJSON - writes the result as a single line of JSON, so the output can be parsed line by line
+func writeNDJSON(w io.Writer, result SearchResult) error {
+	return json.NewEncoder(w).Encode(result)
+}
+
+// printer writes the results of a run in the format chosen by the flags: a line per check by default,
+// a JSON object per check with ndjson, or a single document with json or a template once the run is done
+type printer struct {
+	w      io.Writer
+	search *Search
+	json   bool
+	ndjson bool
+	tmpl   *template.Template
+}
+
+// Result - writes a result as soon as its check is done, the json and template formats wait for Report
+func (p *printer) Result(result SearchResult) {
+	switch {
+	case p.ndjson:
+		if err := writeNDJSON(p.w, result); err != nil {
+			slog.Error("Cannot write result", "url", result.Address, "error", err)
+		}
+	case !p.json && p.tmpl == nil:
+		fmt.Fprintln(p.w, p.search.Format(result))
+	}
+}
+
+// Report - writes what is left once all checks are done: the JSON document, the template or the statistics
+func (p *printer) Report(report *HealthCheckResult) {
+	switch {
+	case p.ndjson:
+		// results were streamed as they came
+	case p.json:
+		if err := writeJSON(p.w, report); err != nil {
+			slog.Error("Cannot write results", "error", err)
+		}
+	case p.tmpl != nil:
+		if err := writeTemplate(p.w, p.tmpl, p.search.Protocol, report); err != nil {
+			slog.Error("Cannot write results", "error", err)
+		}
+	default:
+		for _, stats := range report.Statistics {
+			fmt.Fprintln(p.w, stats)
+		}
+	}
+}
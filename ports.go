@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// parsePorts parses a list of ports and port ranges, ex: 80,443,8000-8010
+func parsePorts(s string) ([]string, error) {
+	ports := make([]string, 0)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			// service names like https are resolved by the dialer
+			if _, err := strconv.Atoi(part); err == nil && !validPort(part) {
+				return nil, errors.New("invalid port: " + part)
+			}
+			ports = append(ports, part)
+			continue
+		}
+
+		if !validPort(from) || !validPort(to) {
+			return nil, errors.New("invalid port range: " + part)
+		}
+		fromPort, _ := strconv.Atoi(from)
+		toPort, _ := strconv.Atoi(to)
+		if fromPort > toPort {
+			return nil, errors.New("invalid port range: " + part)
+		}
+		for port := fromPort; port <= toPort; port++ {
+			ports = append(ports, strconv.Itoa(port))
+		}
+	}
+
+	if len(ports) == 0 {
+		return nil, errors.New("port can't be empty")
+	}
+
+	return ports, nil
+}
+
+// validPort reports whether port is a number between 1 and 65535
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// Expand - returns a target per port for every url without an explicit port,
+// ex: example.com with ports 80,443 is example.com:80 and example.com:443
+func (search *Search) Expand(urls []string) []string {
+	targets := make([]string, 0, len(urls))
+	for _, url := range urls {
+		host, port, path := parseTarget(url, "")
+		if port != "" || len(search.Ports) < 2 {
+			targets = append(targets, url)
+			continue
+		}
+		for _, port := range search.Ports {
+			targets = append(targets, net.JoinHostPort(host, port)+path)
+		}
+	}
+	return targets
+}
+
+// Dedup - drops urls which check the same host, port and path as an earlier one,
+// ex: example.com and example.com:80 with the default port 80. With reject a duplicate is an error.
+func (search *Search) Dedup(urls []string, reject bool) ([]string, error) {
+	seen := make(map[string]bool, len(urls))
+	targets := make([]string, 0, len(urls))
+	for _, url := range urls {
+		host, port, path := parseTarget(url, search.Port)
+		key := net.JoinHostPort(host, port) + path
+		if seen[key] {
+			if reject {
+				return nil, fmt.Errorf("duplicate url: %v", url)
+			}
+			slog.Warn("Duplicate url skipped", "url", url)
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, url)
+	}
+	return targets, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// pushgatewayLabels tell the checks apart, path and ip are empty unless the check has them
+var pushgatewayLabels = []string{"url", "port", "path", "ip", "protocol"}
+
+// pushgatewayRecorder collects check results as Prometheus metrics and pushes them to a Pushgateway on Close,
+// so a short-lived run leaves its metrics behind for the next scrape
+type pushgatewayRecorder struct {
+	pusher   *push.Pusher
+	retries  int
+	recorded bool
+
+	checks       *prometheus.CounterVec
+	failed       *prometheus.CounterVec
+	up           *prometheus.GaugeVec
+	responseTime *prometheus.GaugeVec
+}
+
+// newPushgatewayRecorder - creates a recorder pushing to the group of job, a failed push is retried retries times,
+// ex: http://localhost:9091
+func newPushgatewayRecorder(serverURL, job string, retries int) (*pushgatewayRecorder, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid pushgateway url: %v", serverURL)
+	}
+	if job == "" {
+		return nil, fmt.Errorf("pushgateway job is required")
+	}
+	if retries < 0 {
+		return nil, fmt.Errorf("invalid pushgateway retries: %v", retries)
+	}
+
+	r := &pushgatewayRecorder{
+		retries: retries,
+		checks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "urlchecker_checks_total",
+			Help: "Checks done in the run.",
+		}, pushgatewayLabels),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "urlchecker_checks_failed_total",
+			Help: "Failed checks in the run.",
+		}, pushgatewayLabels),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "urlchecker_up",
+			Help: "Whether the last check succeeded.",
+		}, pushgatewayLabels),
+		responseTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "urlchecker_response_time_seconds",
+			Help: "Response time of the last check.",
+		}, pushgatewayLabels),
+	}
+	r.pusher = push.New(serverURL, job).
+		Client(&http.Client{Timeout: 10 * time.Second}).
+		Collector(r.checks).
+		Collector(r.failed).
+		Collector(r.up).
+		Collector(r.responseTime)
+	return r, nil
+}
+
+// RecordCheck - counts the checks and failures of a result, the up state and the response time keep the last check
+func (r *pushgatewayRecorder) RecordCheck(protocol string, result SearchResult) error {
+	labels := prometheus.Labels{
+		"url":      result.Address,
+		"port":     result.Port,
+		"path":     result.Path,
+		"ip":       result.IP,
+		"protocol": protocol,
+	}
+
+	r.recorded = true
+	r.checks.With(labels).Inc()
+	up := 1.0
+	if result.State != "Success" {
+		r.failed.With(labels).Inc()
+		up = 0
+	} else {
+		// every series of a run exists, a url without failures reports 0
+		r.failed.With(labels).Add(0)
+	}
+	r.up.With(labels).Set(up)
+	r.responseTime.With(labels).Set(result.ResponseTime.Seconds())
+	return nil
+}
+
+// Close - replaces the metrics of the job group with the ones of the run
+func (r *pushgatewayRecorder) Close() error {
+	if !r.recorded {
+		return nil
+	}
+
+	var err error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Second)
+		}
+		if err = r.pusher.Push(); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot push to pushgateway: %v", err)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// hostLimiter limits how many checks per second start against every host, so a backend behind
+// many urls or ports is not hammered. It is shared by all checks of a run.
+type hostLimiter struct {
+	limit rate.Limit
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// newHostLimiter - creates a limiter allowing perSecond checks per second for every host, ex: 0.5 is a check every 2s
+func newHostLimiter(perSecond float64) *hostLimiter {
+	return &hostLimiter{
+		limit:    rate.Limit(perSecond),
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// Wait - blocks until a check of host is allowed or ctx is done
+func (l *hostLimiter) Wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	limiter, ok := l.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, 1)
+		l.limiters[host] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
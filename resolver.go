@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// newResolver - creates a resolver sending every query to the nameserver, ex: 10.0.0.2 or 10.0.0.2:5353
+func newResolver(nameserver string) (*net.Resolver, error) {
+	host, port := splitHostPort(nameserver, "53")
+	if net.ParseIP(host) == nil {
+		return nil, errors.New("invalid resolver: " + nameserver + ", ex: 10.0.0.2:53")
+	}
+	addr := net.JoinHostPort(host, port)
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}, nil
+}
+
+// fallbackDelay is the head start of the first address family before the other one is dialed too,
+// the same as the default of net.Dialer
+const fallbackDelay = 300 * time.Millisecond
+
+// lookupDial - returns a dial function which resolves host names with lookup and dials the addresses
+// with forward. Like net.Dialer it races the two address families (Happy Eyeballs): the family of
+// the first address gets a head start of fallbackDelay, the addresses of a family are dialed in turn.
+func lookupDial(lookup func(ctx context.Context, host string) ([]net.IPAddr, error), forward dialFunc) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return forward(ctx, network, address)
+		}
+
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			primaries, fallbacks []string
+			primaryIPv4          bool
+		)
+		for _, addr := range addrs {
+			ipv4 := addr.IP.To4() != nil
+			if strings.HasSuffix(network, "4") && !ipv4 || strings.HasSuffix(network, "6") && ipv4 {
+				continue
+			}
+			if len(primaries) == 0 {
+				primaryIPv4 = ipv4
+			}
+			target := net.JoinHostPort(addr.String(), port)
+			if ipv4 == primaryIPv4 {
+				primaries = append(primaries, target)
+			} else {
+				fallbacks = append(fallbacks, target)
+			}
+		}
+		if len(primaries) == 0 {
+			return nil, &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+		}
+		if len(fallbacks) == 0 {
+			return dialSerial(ctx, network, primaries, forward)
+		}
+		return dialParallel(ctx, network, primaries, fallbacks, forward)
+	}
+}
+
+// dialSerial - dials the addresses in turn until one answers
+func dialSerial(ctx context.Context, network string, addresses []string, forward dialFunc) (net.Conn, error) {
+	var err error
+	for _, address := range addresses {
+		var conn net.Conn
+		conn, err = forward(ctx, network, address)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+// dialParallel - dials the primaries and, after fallbackDelay or once they failed, the fallbacks too.
+// The first connection wins and the other dial is canceled.
+func dialParallel(ctx context.Context, network string, primaries, fallbacks []string, forward dialFunc) (net.Conn, error) {
+	type dialResult struct {
+		conn    net.Conn
+		err     error
+		primary bool
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, 2)
+	start := func(addresses []string, primary bool) {
+		go func() {
+			conn, err := dialSerial(ctx, network, addresses, forward)
+			results <- dialResult{conn: conn, err: err, primary: primary}
+		}()
+	}
+	start(primaries, true)
+
+	timer := time.NewTimer(fallbackDelay)
+	defer timer.Stop()
+
+	var primaryErr, fallbackErr error
+	pending, fallbackStarted := 1, false
+	for {
+		select {
+		case <-timer.C:
+			if !fallbackStarted {
+				start(fallbacks, false)
+				pending, fallbackStarted = pending+1, true
+			}
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// a connection of the losing family is closed once its dial returns
+				if pending > 0 {
+					go func() {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}()
+				}
+				return result.conn, nil
+			}
+			if result.primary {
+				primaryErr = result.err
+			} else {
+				fallbackErr = result.err
+			}
+			if !fallbackStarted {
+				start(fallbacks, false)
+				pending, fallbackStarted = pending+1, true
+			}
+			if pending == 0 {
+				if primaryErr != nil {
+					return nil, primaryErr
+				}
+				return nil, fallbackErr
+			}
+		}
+	}
+}
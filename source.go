@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// dialFunc opens a connection, it is how checks reach the network
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// parseSourceAddr parses the source address of checks and makes sure it belongs to this host
+func parseSourceAddr(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.New("invalid source address: " + s)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return ip, nil
+		}
+	}
+
+	return nil, errors.New("source address " + s + " is not assigned to any interface of this host")
+}
+
+// newDial - returns a dial function whose connections originate from the source address, when set,
+// and whose host names go to the resolver, when set. It keeps racing IPv4 and IPv6 like the zero Dialer.
+func newDial(source net.IP, resolver *net.Resolver) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialer := net.Dialer{Resolver: resolver}
+		if source != nil {
+			switch network {
+			case "udp", "udp4", "udp6":
+				dialer.LocalAddr = &net.UDPAddr{IP: source}
+			default:
+				dialer.LocalAddr = &net.TCPAddr{IP: source}
+			}
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+}
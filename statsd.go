@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Recorder records the results of checks to a metrics backend. Close flushes what
+// is buffered and releases the backend once all checks are done.
+type Recorder interface {
+	RecordCheck(protocol string, result SearchResult) error
+	Close() error
+}
+
+// statsdRecorder sends check results as DogStatsD counters, timers and gauges over udp
+type statsdRecorder struct {
+	conn net.Conn
+}
+
+// newStatsdRecorder - creates a recorder sending to the StatsD server, ex: localhost:8125
+func newStatsdRecorder(addr string) (*statsdRecorder, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot use statsd address %v: %v", addr, err)
+	}
+	return &statsdRecorder{conn: conn}, nil
+}
+
+// RecordCheck - sends a check count, a failure count, the response time with its phases and the up state of a result
+func (r *statsdRecorder) RecordCheck(protocol string, result SearchResult) error {
+	tags := fmt.Sprintf("|#url:%v,port:%v,protocol:%v", statsdTag(result.Address), statsdTag(result.Port), protocol)
+	// the path and the address tell apart the checks of one host and port
+	if result.Path != "" {
+		tags += ",path:" + statsdTag(result.Path)
+	}
+	if result.IP != "" {
+		tags += ",ip:" + statsdTag(result.IP)
+	}
+
+	up := 0
+	lines := []string{"urlchecker.checks_total:1|c" + tags}
+	if result.State == "Success" {
+		up = 1
+	} else {
+		lines = append(lines, "urlchecker.checks_failed_total:1|c"+tags)
+	}
+	lines = append(lines,
+		fmt.Sprintf("urlchecker.response_time:%.3f|ms%v", float64(result.ResponseTime.Microseconds())/1000, tags),
+		fmt.Sprintf("urlchecker.up:%v|g%v", up, tags),
+	)
+	for _, phase := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"dns_time", result.DNSTime},
+		{"connect_time", result.ConnectTime},
+		{"tls_time", result.TLSTime},
+		{"ttfb", result.TTFB},
+	} {
+		if phase.duration > 0 {
+			lines = append(lines, fmt.Sprintf("urlchecker.%v:%.3f|ms%v", phase.name, float64(phase.duration.Microseconds())/1000, tags))
+		}
+	}
+
+	_, err := r.conn.Write([]byte(strings.Join(lines, "\n")))
+	return err
+}
+
+func (r *statsdRecorder) Close() error {
+	return r.conn.Close()
+}
+
+// statsdTag removes characters which separate metrics and tags in the StatsD protocol
+func statsdTag(value string) string {
+	return strings.NewReplacer("|", "_", ",", "_", "#", "_", "\n", "_").Replace(value)
+}
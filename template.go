@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are helpers available in output templates in addition to the built-in printf
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// duration rounds a duration for humans, ex: 12.3ms
+	"duration": func(d time.Duration) string {
+		return d.Round(100 * time.Microsecond).String()
+	},
+	// seconds converts a duration to fractional seconds, ex: 0.0123
+	"seconds": func(d time.Duration) float64 {
+		return d.Seconds()
+	},
+}
+
+// templateData is what output templates are executed against, ex: {{range .Results}}{{.Address}}{{end}}
+type templateData struct {
+	*HealthCheckResult
+	Protocol string
+}
+
+// parseTemplate - parses an output template given inline, which has an action like {{.Results}}, or from a file
+func parseTemplate(value string) (*template.Template, error) {
+	text := value
+	if !strings.Contains(value, "{{") {
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return nil, errors.New("Cannot open file: " + value)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.New("invalid template: " + err.Error())
+	}
+	return tmpl, nil
+}
+
+// writeTemplate - writes all results formatted by the output template
+func writeTemplate(w io.Writer, tmpl *template.Template, protocol string, report *HealthCheckResult) error {
+	return tmpl.Execute(w, templateData{HealthCheckResult: report, Protocol: protocol})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math"
+	"net"
+	"time"
+)
+
+// checkTLS - checks url address by a TLS handshake and reports how many days the certificate is valid
+func (search *Search) checkTLS(ctx context.Context, result SearchResult) SearchResult {
+	startTime := time.Now()
+	conn, err := search.dialTraced(ctx, "tcp", net.JoinHostPort(result.Address, result.Port))
+	if err != nil {
+		result.ResponseTime = time.Since(startTime)
+		result.State = "Failed"
+		result.Reason = failureReason(err)
+		return result
+	}
+	defer conn.Close()
+	result.Family = addrFamily(conn.RemoteAddr())
+	result.answered = true
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         result.Address,
+		InsecureSkipVerify: search.InsecureSkipVerify,
+	})
+	err = tlsConn.HandshakeContext(ctx)
+	result.ResponseTime = time.Since(startTime)
+
+	// a failed verification still tells the certificate, so an expired one is reported as such
+	var certs []*x509.Certificate
+	var verifyErr *tls.CertificateVerificationError
+	switch {
+	case err == nil:
+		certs = tlsConn.ConnectionState().PeerCertificates
+	case errors.As(err, &verifyErr):
+		certs = verifyErr.UnverifiedCertificates
+	}
+	if len(certs) == 0 {
+		result.State = "TLSFailed"
+		result.Reason = "tls_error"
+		return result
+	}
+
+	// days are rounded down, so a certificate expired a few hours ago has -1 days and not 0
+	days := int(math.Floor(time.Until(certs[0].NotAfter).Hours() / 24))
+	result.CertExpiryDays = &days
+	switch {
+	case time.Now().After(certs[0].NotAfter):
+		result.State = "TLSFailed"
+		result.Reason = "cert_expired"
+	case err != nil:
+		result.State = "TLSFailed"
+		result.Reason = "tls_error"
+	default:
+		result.State = "Success"
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/extimsu/urlchecker/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer starts the spans of checks, it is a no-op until setupTracing installs an exporter
+var tracer = otel.Tracer("github.com/extimsu/urlchecker")
+
+// setupTracing - exports spans over OTLP/HTTP to the endpoint, ex: http://localhost:4318.
+// The returned function flushes the spans left and must be called before exiting.
+func setupTracing(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName("urlchecker"),
+			semconv.ServiceVersion(version.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	tracer = provider.Tracer("github.com/extimsu/urlchecker")
+
+	return provider.Shutdown, nil
+}
+
+// dialTraced - dials like search.dial in the preferred address family inside a dial span
+func (search *Search) dialTraced(ctx context.Context, network, address string) (net.Conn, error) {
+	network = search.network(network)
+	ctx, span := tracer.Start(ctx, "dial", trace.WithAttributes(
+		attribute.String("net.network", network),
+		attribute.String("net.address", address),
+	))
+	defer span.End()
+
+	conn, err := search.dial(ctx, network, address)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return conn, err
+}
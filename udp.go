@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// udpProbes are built-in payloads that make well known udp services respond
+var udpProbes = map[string][]byte{
+	// dns: a recursive query for the NS records of the root zone
+	"dns": {0x12, 0x34, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+	// ntp: a version 3 client request
+	"ntp": append([]byte{0x1b}, make([]byte, 47)...),
+	// stun: a binding request with the magic cookie and a fixed transaction id
+	"stun": {0x00, 0x01, 0x00, 0x00, 0x21, 0x12, 0xa4, 0x42, 0x75, 0x72, 0x6c, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x00, 0x01},
+}
+
+// udpProbe returns the payload of a built-in probe, ex: dns, ntp or stun
+func udpProbe(name string) ([]byte, error) {
+	payload, ok := udpProbes[name]
+	if !ok {
+		return nil, errors.New("unknown udp probe: " + name + ", use dns, ntp or stun")
+	}
+	return payload, nil
+}
+
+// checkUDP - sends a probe over udp and waits for any response, as udp has no handshake to rely on
+func (search *Search) checkUDP(ctx context.Context, conn net.Conn, result SearchResult) SearchResult {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	payload := search.UDPPayload
+	if payload == nil {
+		payload = []byte(unescapeSend.Replace(search.Send))
+	}
+
+	startTime := time.Now()
+	if _, err := conn.Write(payload); err != nil {
+		result.State = "Failed"
+		result.Reason = failureReason(err)
+		return result
+	}
+
+	// an ICMP port unreachable is reported by the read as connection refused
+	buf := make([]byte, maxBannerSize)
+	n, err := conn.Read(buf)
+	result.ResponseTime += time.Since(startTime)
+	if err != nil {
+		result.State = "Failed"
+		result.Reason = failureReason(err)
+		return result
+	}
+	result.answered = true
+
+	if search.Expect != "" && !bytes.Contains(buf[:n], []byte(search.Expect)) {
+		result.State = "Failed"
+		result.Reason = "unexpected_response"
+		return result
+	}
+
+	result.State = "Success"
+	return result
+}